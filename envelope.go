@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// kmsEnvelope is the self-describing JSON wrapper around a value that has
+// been client-side encrypted with a KMS-generated data key. It's used both
+// as an S3 object body and as a Secrets Manager SecretString.
+type kmsEnvelope struct {
+	KMSKeyARN        string `json:"kms_key_arn"`
+	EncryptedDataKey string `json:"encrypted_data_key"`
+	Nonce            string `json:"nonce"`
+	Ciphertext       string `json:"ciphertext"`
+	AAD              string `json:"aad"`
+}
+
+// looksLikeEnvelope reports whether data is a JSON-encoded kmsEnvelope,
+// used to decide whether a downloaded/fetched value needs decrypting.
+func looksLikeEnvelope(data []byte) bool {
+	var probe struct {
+		KMSKeyARN string `json:"kms_key_arn"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return probe.KMSKeyARN != ""
+}
+
+// parseEncryptionContext turns repeated --encryption-context k=v flag
+// values into the map KMS expects.
+func parseEncryptionContext(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	ctx := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid --encryption-context entry %q (expected key=value)", pair)
+		}
+		ctx[parts[0]] = parts[1]
+	}
+	return ctx, nil
+}
+
+// encryptionContextAAD derives a canonical, order-independent byte string
+// from an encryption context to use as AES-GCM additional authenticated
+// data, so the stored ciphertext is bound to that context.
+func encryptionContextAAD(encryptionContext map[string]string) []byte {
+	keys := make([]string, 0, len(encryptionContext))
+	for k := range encryptionContext {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(encryptionContext[k])
+		sb.WriteByte(';')
+	}
+	return []byte(sb.String())
+}
+
+// encryptWithKMSEnvelope generates a KMS data key and uses it to seal
+// plaintext with AES-256-GCM, returning the JSON-encoded envelope.
+func encryptWithKMSEnvelope(ctx context.Context, client *kms.Client, keyID string, plaintext []byte, encryptionContext map[string]string) ([]byte, error) {
+	genOut, err := client.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:             &keyID,
+		KeySpec:           types.DataKeySpecAes256,
+		EncryptionContext: encryptionContext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generating KMS data key: %w", err)
+	}
+
+	block, err := aes.NewCipher(genOut.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("initializing AES cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("initializing AES-GCM: %w", err)
+	}
+
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+
+	aad := encryptionContextAAD(encryptionContext)
+	ciphertext := gcm.Seal(nil, nonce, plaintext, aad)
+
+	envelope := kmsEnvelope{
+		KMSKeyARN:        *genOut.KeyId,
+		EncryptedDataKey: base64.StdEncoding.EncodeToString(genOut.CiphertextBlob),
+		Nonce:            base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext:       base64.StdEncoding.EncodeToString(ciphertext),
+		AAD:              base64.StdEncoding.EncodeToString(aad),
+	}
+
+	return json.Marshal(envelope)
+}
+
+// decryptKMSEnvelope unwraps the data key via kms.Decrypt and opens the
+// AES-256-GCM ciphertext. The AAD is recomputed from the caller-supplied
+// encryption context rather than trusted from the envelope itself, so a
+// tampered envelope or a mismatched context both fail to decrypt.
+func decryptKMSEnvelope(ctx context.Context, client *kms.Client, data []byte, encryptionContext map[string]string) ([]byte, error) {
+	var envelope kmsEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("parsing envelope: %w", err)
+	}
+
+	encryptedDataKey, err := base64.StdEncoding.DecodeString(envelope.EncryptedDataKey)
+	if err != nil {
+		return nil, fmt.Errorf("decoding encrypted data key: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(envelope.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("decoding nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(envelope.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decoding ciphertext: %w", err)
+	}
+
+	decryptOut, err := client.Decrypt(ctx, &kms.DecryptInput{
+		CiphertextBlob:    encryptedDataKey,
+		EncryptionContext: encryptionContext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decrypting data key: %w", err)
+	}
+
+	block, err := aes.NewCipher(decryptOut.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("initializing AES cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("initializing AES-GCM: %w", err)
+	}
+
+	aad := encryptionContextAAD(encryptionContext)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting ciphertext (tampered envelope or wrong encryption context): %w", err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+func TestParseEncryptionContext(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		got, err := parseEncryptionContext(nil)
+		if err != nil {
+			t.Fatalf("parseEncryptionContext(nil) returned error: %v", err)
+		}
+		if got != nil {
+			t.Errorf("parseEncryptionContext(nil) = %v, want nil", got)
+		}
+	})
+
+	t.Run("valid pairs", func(t *testing.T) {
+		got, err := parseEncryptionContext([]string{"env=prod", "team=platform"})
+		if err != nil {
+			t.Fatalf("parseEncryptionContext returned error: %v", err)
+		}
+		want := map[string]string{"env": "prod", "team": "platform"}
+		if len(got) != len(want) || got["env"] != want["env"] || got["team"] != want["team"] {
+			t.Errorf("parseEncryptionContext = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		if _, err := parseEncryptionContext([]string{"env"}); err == nil {
+			t.Error("parseEncryptionContext(\"env\") expected error, got nil")
+		}
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		if _, err := parseEncryptionContext([]string{"=prod"}); err == nil {
+			t.Error("parseEncryptionContext(\"=prod\") expected error, got nil")
+		}
+	})
+}
+
+func TestEncryptionContextAAD(t *testing.T) {
+	a := encryptionContextAAD(map[string]string{"b": "2", "a": "1"})
+	b := encryptionContextAAD(map[string]string{"a": "1", "b": "2"})
+	if string(a) != string(b) {
+		t.Errorf("encryptionContextAAD should be order-independent, got %q vs %q", a, b)
+	}
+	if want := "a=1;b=2;"; string(a) != want {
+		t.Errorf("encryptionContextAAD = %q, want %q", a, want)
+	}
+
+	diff := encryptionContextAAD(map[string]string{"a": "2"})
+	if string(diff) == string(a) {
+		t.Error("encryptionContextAAD should differ for different contexts")
+	}
+}
+
+func TestLooksLikeEnvelope(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"envelope json", []byte(`{"kms_key_arn":"arn:aws:kms:us-east-1:1:key/abc","ciphertext":"xx"}`), true},
+		{"plain json", []byte(`{"foo":"bar"}`), false},
+		{"not json", []byte(`hello world`), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksLikeEnvelope(tt.data); got != tt.want {
+				t.Errorf("looksLikeEnvelope(%q) = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
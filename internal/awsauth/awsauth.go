@@ -0,0 +1,91 @@
+// Package awsauth centralizes how aws-vault commands turn CLI flags into an
+// aws.Config, so profile selection, assume-role, MFA and SSO all behave the
+// same way regardless of which command is run.
+package awsauth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Options holds the credential-related flags shared by every command.
+type Options struct {
+	// Profile selects a profile from the shared AWS config/credentials files.
+	Profile string
+	// Region overrides the region resolved from the profile/environment.
+	Region string
+
+	// AssumeRoleARN, when set, causes the resolved credentials to be
+	// exchanged for a role session via sts:AssumeRole.
+	AssumeRoleARN   string
+	RoleSessionName string
+	ExternalID      string
+	// MFASerial, when set, prompts for an MFA token on stdin when assuming
+	// the role.
+	MFASerial string
+
+	// SSOStartURL, SSOAccountID and SSORoleName select an AWS IAM Identity
+	// Center (SSO) account/role directly, without needing a profile entry.
+	SSOStartURL  string
+	SSOAccountID string
+	SSORoleName  string
+	// SSORegion is the region the SSO start URL is hosted in. Defaults to
+	// Region when unset.
+	SSORegion string
+}
+
+// LoadConfig resolves an aws.Config from the given options, applying
+// profile selection, SSO, and assume-role/MFA on top of the SDK's default
+// credential chain.
+func LoadConfig(ctx context.Context, opts Options) (aws.Config, error) {
+	var loadOpts []func(*config.LoadOptions) error
+
+	if opts.Profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(opts.Profile))
+	}
+	if opts.Region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(opts.Region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("loading AWS SDK config: %w", err)
+	}
+
+	if opts.SSOStartURL != "" {
+		ssoRegion := opts.SSORegion
+		if ssoRegion == "" {
+			ssoRegion = cfg.Region
+		}
+		ssoClient := sso.NewFromConfig(cfg, func(o *sso.Options) {
+			o.Region = ssoRegion
+		})
+		provider := ssocreds.New(ssoClient, opts.SSOAccountID, opts.SSORoleName, opts.SSOStartURL)
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	if opts.AssumeRoleARN != "" {
+		stsClient := sts.NewFromConfig(cfg)
+		provider := stscreds.NewAssumeRoleProvider(stsClient, opts.AssumeRoleARN, func(o *stscreds.AssumeRoleOptions) {
+			if opts.RoleSessionName != "" {
+				o.RoleSessionName = opts.RoleSessionName
+			}
+			if opts.ExternalID != "" {
+				o.ExternalID = aws.String(opts.ExternalID)
+			}
+			if opts.MFASerial != "" {
+				o.SerialNumber = aws.String(opts.MFASerial)
+				o.TokenProvider = stscreds.StdinTokenProvider
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	return cfg, nil
+}
@@ -9,13 +9,52 @@ import (
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/Trev2689/aws-vault/internal/awsauth"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/spf13/cobra"
 )
 
+// addAuthFlags registers the credential-resolution flags shared by every
+// command: profile selection, assume-role/MFA, and SSO.
+func addAuthFlags(cmd *cobra.Command) {
+	cmd.Flags().String("profile", "", "Named profile from the shared AWS config/credentials files")
+	cmd.Flags().String("assume-role-arn", "", "ARN of a role to assume before making API calls")
+	cmd.Flags().String("role-session-name", "", "Session name to use when assuming --assume-role-arn")
+	cmd.Flags().String("external-id", "", "External ID to pass when assuming --assume-role-arn")
+	cmd.Flags().String("mfa-serial", "", "MFA device serial or ARN; prompts for a token code on stdin")
+	cmd.Flags().String("sso-start-url", "", "AWS IAM Identity Center start URL")
+	cmd.Flags().String("sso-account-id", "", "AWS account ID to use with --sso-start-url")
+	cmd.Flags().String("sso-role-name", "", "Permission set role name to use with --sso-start-url")
+}
+
+// authOptionsFromFlags reads the flags registered by addAuthFlags into an
+// awsauth.Options, with the given region as the region override. Commands
+// without their own --region flag pass an empty string.
+func authOptionsFromFlags(cmd *cobra.Command, region string) awsauth.Options {
+	profile, _ := cmd.Flags().GetString("profile")
+	assumeRoleARN, _ := cmd.Flags().GetString("assume-role-arn")
+	roleSessionName, _ := cmd.Flags().GetString("role-session-name")
+	externalID, _ := cmd.Flags().GetString("external-id")
+	mfaSerial, _ := cmd.Flags().GetString("mfa-serial")
+	ssoStartURL, _ := cmd.Flags().GetString("sso-start-url")
+	ssoAccountID, _ := cmd.Flags().GetString("sso-account-id")
+	ssoRoleName, _ := cmd.Flags().GetString("sso-role-name")
+
+	return awsauth.Options{
+		Profile:         profile,
+		Region:          region,
+		AssumeRoleARN:   assumeRoleARN,
+		RoleSessionName: roleSessionName,
+		ExternalID:      externalID,
+		MFASerial:       mfaSerial,
+		SSOStartURL:     ssoStartURL,
+		SSOAccountID:    ssoAccountID,
+		SSORoleName:     ssoRoleName,
+	}
+}
+
 func main() {
 	var rootCmd = &cobra.Command{
 		Use:   "aws-vault",
@@ -31,6 +70,10 @@ func main() {
 	rootCmd.AddCommand(downloadCmd)
 	rootCmd.AddCommand(updateSecretCmd)
 	rootCmd.AddCommand(createSecretCmd)
+	rootCmd.AddCommand(getSecretCmd)
+	rootCmd.AddCommand(rotateSecretCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(syncDownCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -47,36 +90,82 @@ var uploadCmd = &cobra.Command{
 		bucketName, _ := cmd.Flags().GetString("bucket")
 		filePath, _ := cmd.Flags().GetString("file")
 		subdirectory, _ := cmd.Flags().GetString("subdirectory")
+		partSizeMB, _ := cmd.Flags().GetInt64("part-size")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		checksumAlgorithm, _ := cmd.Flags().GetString("checksum-algorithm")
+		showProgress, _ := cmd.Flags().GetBool("progress")
+		sseValue, _ := cmd.Flags().GetString("sse")
+		sseKMSKeyID, _ := cmd.Flags().GetString("sse-kms-key-id")
+		sseCustomerKey, _ := cmd.Flags().GetString("sse-customer-key")
+		encryptWithKMS, _ := cmd.Flags().GetString("encrypt-with-kms")
+		encryptionContextFlags, _ := cmd.Flags().GetStringArray("encryption-context")
 
 		if bucketName == "" || filePath == "" {
 			fmt.Println("Please provide all required input parameters: --bucket and --file")
 			os.Exit(1)
 		}
 
-		// Load AWS SDK configuration
-		cfg, err := config.LoadDefaultConfig(context.Background())
+		checksumAlgo, err := parseChecksumAlgorithm(checksumAlgorithm)
 		if err != nil {
-			fmt.Println("Error loading AWS SDK config:", err)
+			fmt.Println(err)
 			os.Exit(1)
 		}
 
-		// Create S3 client with the config from above
-		client := s3.NewFromConfig(cfg)
+		sse, err := parseSSEFlags(sseValue, sseKMSKeyID, sseCustomerKey)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 
-		// Read file contents
-		data, err := ioutil.ReadFile(filePath)
+		encryptionContext, err := parseEncryptionContext(encryptionContextFlags)
 		if err != nil {
-			fmt.Println("Error reading file:", err)
+			fmt.Println(err)
 			os.Exit(1)
 		}
 
-		// Upload file to S3
-		_, err = client.PutObject(context.Background(), &s3.PutObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(subdirectory + "/" + filePath),
-			Body:   bytes.NewReader(data),
-		})
+		// Resolve AWS SDK configuration (profile, assume-role/MFA, SSO)
+		cfg, err := awsauth.LoadConfig(context.Background(), authOptionsFromFlags(cmd, ""))
 		if err != nil {
+			fmt.Println("Error loading AWS SDK config:", err)
+			os.Exit(1)
+		}
+
+		// Create S3 client with the config from above
+		client := s3.NewFromConfig(cfg)
+
+		key := subdirectory + "/" + filePath
+
+		if encryptWithKMS != "" {
+			// Client-side envelope encryption needs the whole file in memory
+			// to seal it before it leaves the process, so this bypasses the
+			// streaming upload path.
+			plaintext, err := ioutil.ReadFile(filePath)
+			if err != nil {
+				fmt.Println("Error reading file:", err)
+				os.Exit(1)
+			}
+
+			kmsClient := kms.NewFromConfig(cfg)
+			envelope, err := encryptWithKMSEnvelope(context.Background(), kmsClient, encryptWithKMS, plaintext, encryptionContext)
+			if err != nil {
+				fmt.Println("Error encrypting file with KMS:", err)
+				os.Exit(1)
+			}
+
+			if _, err := client.PutObject(context.Background(), &s3.PutObjectInput{
+				Bucket: &bucketName,
+				Key:    &key,
+				Body:   bytes.NewReader(envelope),
+			}); err != nil {
+				fmt.Println("Error uploading file to S3:", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("File uploaded to S3 successfully.")
+			return
+		}
+
+		if err := uploadFileStreaming(client, bucketName, key, filePath, partSizeMB, concurrency, checksumAlgo, showProgress, sse); err != nil {
 			fmt.Println("Error uploading file to S3:", err)
 			os.Exit(1)
 		}
@@ -94,14 +183,32 @@ var downloadCmd = &cobra.Command{
 		bucketName, _ := cmd.Flags().GetString("bucket")
 		filePath, _ := cmd.Flags().GetString("file")
 		subdirectory, _ := cmd.Flags().GetString("subdirectory")
+		partSizeMB, _ := cmd.Flags().GetInt64("part-size")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		showProgress, _ := cmd.Flags().GetBool("progress")
+		sseCustomerKey, _ := cmd.Flags().GetString("sse-customer-key")
+		decrypt, _ := cmd.Flags().GetBool("decrypt")
+		encryptionContextFlags, _ := cmd.Flags().GetStringArray("encryption-context")
 
 		if bucketName == "" || filePath == "" {
 			fmt.Println("Please provide all required input parameters: --bucket and --file")
 			os.Exit(1)
 		}
 
-		// Load AWS SDK configuration
-		cfg, err := config.LoadDefaultConfig(context.Background())
+		sse, err := parseSSEFlags("", "", sseCustomerKey)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		encryptionContext, err := parseEncryptionContext(encryptionContextFlags)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		// Resolve AWS SDK configuration (profile, assume-role/MFA, SSO)
+		cfg, err := awsauth.LoadConfig(context.Background(), authOptionsFromFlags(cmd, ""))
 		if err != nil {
 			fmt.Println("Error loading AWS SDK config:", err)
 			os.Exit(1)
@@ -110,31 +217,130 @@ var downloadCmd = &cobra.Command{
 		// Create S3 client with the config from above
 		client := s3.NewFromConfig(cfg)
 
-		// Download file from S3
-		resp, err := client.GetObject(context.Background(), &s3.GetObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(subdirectory + "/" + filePath),
-		})
-		if err != nil {
+		key := subdirectory + "/" + filePath
+
+		if decrypt {
+			// Envelope decryption needs the whole object in memory to open
+			// the AES-GCM ciphertext, so this bypasses the streaming
+			// download path.
+			resp, err := client.GetObject(context.Background(), &s3.GetObjectInput{Bucket: &bucketName, Key: &key})
+			if err != nil {
+				fmt.Println("Error downloading file from S3:", err)
+				os.Exit(1)
+			}
+			data, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Println("Error reading file contents:", err)
+				os.Exit(1)
+			}
+
+			kmsClient := kms.NewFromConfig(cfg)
+			plaintext, err := decryptKMSEnvelope(context.Background(), kmsClient, data, encryptionContext)
+			if err != nil {
+				fmt.Println("Error decrypting file:", err)
+				os.Exit(1)
+			}
+
+			if err := ioutil.WriteFile(filePath, plaintext, 0644); err != nil {
+				fmt.Println("Error writing file to disk:", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("File downloaded from S3 successfully.")
+			return
+		}
+
+		if err := downloadFileStreaming(client, bucketName, key, filePath, partSizeMB, concurrency, showProgress, sse); err != nil {
 			fmt.Println("Error downloading file from S3:", err)
 			os.Exit(1)
 		}
 
-		// Read file contents
-		data, err := ioutil.ReadAll(resp.Body)
+		fmt.Println("File downloaded from S3 successfully.")
+	},
+}
+
+// syncOptionsFromFlags reads the flags shared by sync and sync-down.
+func syncOptionsFromFlags(cmd *cobra.Command) syncOptions {
+	bucketName, _ := cmd.Flags().GetString("bucket")
+	subdirectory, _ := cmd.Flags().GetString("subdirectory")
+	dir, _ := cmd.Flags().GetString("dir")
+	workers, _ := cmd.Flags().GetInt("workers")
+	deleteFlag, _ := cmd.Flags().GetBool("delete")
+	include, _ := cmd.Flags().GetStringArray("include")
+	exclude, _ := cmd.Flags().GetStringArray("exclude")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	storageClass, _ := cmd.Flags().GetString("storage-class")
+
+	return syncOptions{
+		Bucket:       bucketName,
+		Prefix:       subdirectory,
+		LocalDir:     dir,
+		Workers:      workers,
+		Delete:       deleteFlag,
+		Include:      include,
+		Exclude:      exclude,
+		DryRun:       dryRun,
+		StorageClass: storageClass,
+	}
+}
+
+// Command to sync a local directory up to an S3 bucket
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync a local directory up to an S3 bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := syncOptionsFromFlags(cmd)
+
+		if opts.Bucket == "" || opts.LocalDir == "" {
+			fmt.Println("Please provide all required input parameters: --bucket and --dir")
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		cfg, err := awsauth.LoadConfig(ctx, authOptionsFromFlags(cmd, ""))
 		if err != nil {
-			fmt.Println("Error reading file contents:", err)
+			fmt.Println("Error loading AWS SDK config:", err)
 			os.Exit(1)
 		}
+		client := s3.NewFromConfig(cfg)
 
-		// Write file contents to disk
-		err = ioutil.WriteFile(filePath, data, 0644)
+		result, err := runSyncUp(ctx, client, opts)
 		if err != nil {
-			fmt.Println("Error writing file to disk:", err)
+			fmt.Println("Error syncing directory to S3:", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("File downloaded from S3 successfully.")
+		fmt.Printf("Sync complete: %d uploaded, %d skipped, %d deleted\n", result.Transferred, result.Skipped, result.Deleted)
+	},
+}
+
+// Command to sync an S3 bucket down to a local directory
+var syncDownCmd = &cobra.Command{
+	Use:   "sync-down",
+	Short: "Sync an S3 bucket down to a local directory",
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := syncOptionsFromFlags(cmd)
+
+		if opts.Bucket == "" || opts.LocalDir == "" {
+			fmt.Println("Please provide all required input parameters: --bucket and --dir")
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		cfg, err := awsauth.LoadConfig(ctx, authOptionsFromFlags(cmd, ""))
+		if err != nil {
+			fmt.Println("Error loading AWS SDK config:", err)
+			os.Exit(1)
+		}
+		client := s3.NewFromConfig(cfg)
+
+		result, err := runSyncDown(ctx, client, opts)
+		if err != nil {
+			fmt.Println("Error syncing directory from S3:", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Sync complete: %d downloaded, %d skipped, %d deleted\n", result.Transferred, result.Skipped, result.Deleted)
 	},
 }
 
@@ -150,6 +356,8 @@ var updateSecretCmd = &cobra.Command{
 		jsonFilePath, _ := cmd.Flags().GetString("json-file")
 		timeout, _ := cmd.Flags().GetDuration("timeout")
 		updateFlag, _ := cmd.Flags().GetBool("update")
+		encryptWithKMS, _ := cmd.Flags().GetString("encrypt-with-kms")
+		encryptionContextFlags, _ := cmd.Flags().GetStringArray("encryption-context")
 
 		if secretName == "" || region == "" || description == "" || jsonFilePath == "" {
 			fmt.Println("Please provide all required input parameters: --name, --region, --description, and --json-file")
@@ -163,17 +371,34 @@ var updateSecretCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		encryptionContext, err := parseEncryptionContext(encryptionContextFlags)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
 		// Create context with timeout
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 
-		// Load AWS SDK configuration with the specified region
-		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+		// Resolve AWS SDK configuration with the specified region (profile,
+		// assume-role/MFA, SSO)
+		cfg, err := awsauth.LoadConfig(ctx, authOptionsFromFlags(cmd, region))
 		if err != nil {
 			fmt.Println("Error loading AWS SDK config:", err)
 			os.Exit(1)
 		}
 
+		if encryptWithKMS != "" {
+			kmsClient := kms.NewFromConfig(cfg)
+			envelope, err := encryptWithKMSEnvelope(ctx, kmsClient, encryptWithKMS, []byte(secretValue), encryptionContext)
+			if err != nil {
+				fmt.Println("Error encrypting secret with KMS:", err)
+				os.Exit(1)
+			}
+			secretValue = string(envelope)
+		}
+
 		// Create Secrets Manager client with the config from above
 		client := secretsmanager.NewFromConfig(cfg)
 
@@ -250,6 +475,8 @@ var createSecretCmd = &cobra.Command{
 		description, _ := cmd.Flags().GetString("description")
 		jsonFilePath, _ := cmd.Flags().GetString("json-file")
 		timeout, _ := cmd.Flags().GetDuration("timeout")
+		encryptWithKMS, _ := cmd.Flags().GetString("encrypt-with-kms")
+		encryptionContextFlags, _ := cmd.Flags().GetStringArray("encryption-context")
 
 		if secretName == "" || region == "" || description == "" || jsonFilePath == "" {
 			fmt.Println("Please provide all required input parameters: --name, --region, --description, and --json-file")
@@ -263,17 +490,34 @@ var createSecretCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		encryptionContext, err := parseEncryptionContext(encryptionContextFlags)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
 		// Create context with timeout
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 
-		// Load AWS SDK configuration with the specified region
-		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+		// Resolve AWS SDK configuration with the specified region (profile,
+		// assume-role/MFA, SSO)
+		cfg, err := awsauth.LoadConfig(ctx, authOptionsFromFlags(cmd, region))
 		if err != nil {
 			fmt.Println("Error loading AWS SDK config:", err)
 			os.Exit(1)
 		}
 
+		if encryptWithKMS != "" {
+			kmsClient := kms.NewFromConfig(cfg)
+			envelope, err := encryptWithKMSEnvelope(ctx, kmsClient, encryptWithKMS, []byte(secretValue), encryptionContext)
+			if err != nil {
+				fmt.Println("Error encrypting secret with KMS:", err)
+				os.Exit(1)
+			}
+			secretValue = string(envelope)
+		}
+
 		// Create Secrets Manager client with the config from above
 		client := secretsmanager.NewFromConfig(cfg)
 
@@ -296,6 +540,70 @@ var createSecretCmd = &cobra.Command{
 	},
 }
 
+// Command to fetch a secret from Secrets Manager, transparently decrypting
+// it if it was stored as a client-side KMS envelope.
+var getSecretCmd = &cobra.Command{
+	Use:   "get-secret",
+	Short: "Get secret from Secrets Manager",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Check required input parameters
+		secretName, _ := cmd.Flags().GetString("name")
+		region, _ := cmd.Flags().GetString("region")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		encryptionContextFlags, _ := cmd.Flags().GetStringArray("encryption-context")
+
+		if secretName == "" || region == "" {
+			fmt.Println("Please provide all required input parameters: --name and --region")
+			os.Exit(1)
+		}
+
+		encryptionContext, err := parseEncryptionContext(encryptionContextFlags)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		// Create context with timeout
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		// Resolve AWS SDK configuration with the specified region (profile,
+		// assume-role/MFA, SSO)
+		cfg, err := awsauth.LoadConfig(ctx, authOptionsFromFlags(cmd, region))
+		if err != nil {
+			fmt.Println("Error loading AWS SDK config:", err)
+			os.Exit(1)
+		}
+
+		// Create Secrets Manager client with the config from above
+		client := secretsmanager.NewFromConfig(cfg)
+
+		getOutput, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &secretName})
+		if err != nil {
+			fmt.Println("Error getting secret:", err)
+			os.Exit(1)
+		}
+
+		if getOutput.SecretString == nil {
+			fmt.Println("Secret has no SecretString value.")
+			os.Exit(1)
+		}
+
+		if looksLikeEnvelope([]byte(*getOutput.SecretString)) {
+			kmsClient := kms.NewFromConfig(cfg)
+			plaintext, err := decryptKMSEnvelope(ctx, kmsClient, []byte(*getOutput.SecretString), encryptionContext)
+			if err != nil {
+				fmt.Println("Error decrypting secret:", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(plaintext))
+			return
+		}
+
+		fmt.Println(*getOutput.SecretString)
+	},
+}
+
 func readSecretFromJSON(filePath string) (string, error) {
 	data, err := ioutil.ReadFile(filePath)
 	if err != nil {
@@ -308,10 +616,53 @@ func init() {
 	uploadCmd.Flags().StringP("bucket", "b", "", "S3 bucket name")
 	uploadCmd.Flags().StringP("file", "f", "", "Path to file to upload")
 	uploadCmd.Flags().StringP("subdirectory", "s", "", "Subdirectory in S3 bucket")
+	uploadCmd.Flags().Int64("part-size", defaultPartSizeMB, "Part size in MB for multipart upload")
+	uploadCmd.Flags().Int("concurrency", defaultConcurrency, "Number of parts to upload concurrently")
+	uploadCmd.Flags().String("checksum-algorithm", "CRC32", "Checksum algorithm to use (CRC32, CRC32C, SHA256)")
+	uploadCmd.Flags().Bool("progress", false, "Show a progress bar while uploading")
+	uploadCmd.Flags().String("sse", "", "Server-side encryption mode (AES256, aws:kms, aws:kms:dsse)")
+	uploadCmd.Flags().String("sse-kms-key-id", "", "KMS key ID to use when --sse is aws:kms or aws:kms:dsse")
+	uploadCmd.Flags().String("sse-customer-key", "", "SSE-C customer key, as a file path or base64 string")
+	uploadCmd.Flags().String("encrypt-with-kms", "", "KMS key ID to client-side encrypt the file with before upload")
+	uploadCmd.Flags().StringArray("encryption-context", nil, "KMS encryption context entry (key=value), repeatable")
 
 	downloadCmd.Flags().StringP("bucket", "b", "", "S3 bucket name")
 	downloadCmd.Flags().StringP("file", "f", "", "Path to file to download")
 	downloadCmd.Flags().StringP("subdirectory", "s", "", "Subdirectory in S3 bucket")
+	downloadCmd.Flags().Int64("part-size", defaultPartSizeMB, "Part size in MB for multipart download")
+	downloadCmd.Flags().Int("concurrency", defaultConcurrency, "Number of parts to download concurrently")
+	downloadCmd.Flags().Bool("progress", false, "Show a progress bar while downloading")
+	downloadCmd.Flags().String("sse-customer-key", "", "SSE-C customer key used when the object was uploaded with --sse-customer-key")
+	downloadCmd.Flags().Bool("decrypt", false, "Decrypt a file uploaded with --encrypt-with-kms after downloading")
+	downloadCmd.Flags().StringArray("encryption-context", nil, "KMS encryption context entry (key=value), repeatable")
+
+	addAuthFlags(uploadCmd)
+	addAuthFlags(downloadCmd)
+	addAuthFlags(updateSecretCmd)
+	addAuthFlags(createSecretCmd)
+	addAuthFlags(getSecretCmd)
+	addAuthFlags(rotateSecretCmd)
+	addAuthFlags(syncCmd)
+	addAuthFlags(syncDownCmd)
+
+	syncCmd.Flags().StringP("bucket", "b", "", "S3 bucket name")
+	syncCmd.Flags().StringP("subdirectory", "s", "", "Subdirectory (prefix) in S3 bucket")
+	syncCmd.Flags().String("dir", "", "Local directory to sync up to S3")
+	syncCmd.Flags().Int("workers", defaultConcurrency, "Number of files to sync in parallel")
+	syncCmd.Flags().Bool("delete", false, "Delete S3 keys with no local counterpart")
+	syncCmd.Flags().StringArray("include", nil, "Glob pattern to include, repeatable")
+	syncCmd.Flags().StringArray("exclude", nil, "Glob pattern to exclude, repeatable")
+	syncCmd.Flags().Bool("dry-run", false, "Show what would be synced without changing anything")
+	syncCmd.Flags().String("storage-class", "", "S3 storage class for uploaded objects (e.g. STANDARD_IA, GLACIER_IR)")
+
+	syncDownCmd.Flags().StringP("bucket", "b", "", "S3 bucket name")
+	syncDownCmd.Flags().StringP("subdirectory", "s", "", "Subdirectory (prefix) in S3 bucket")
+	syncDownCmd.Flags().String("dir", "", "Local directory to sync the bucket down into")
+	syncDownCmd.Flags().Int("workers", defaultConcurrency, "Number of files to sync in parallel")
+	syncDownCmd.Flags().Bool("delete", false, "Delete local files with no counterpart in S3")
+	syncDownCmd.Flags().StringArray("include", nil, "Glob pattern to include, repeatable")
+	syncDownCmd.Flags().StringArray("exclude", nil, "Glob pattern to exclude, repeatable")
+	syncDownCmd.Flags().Bool("dry-run", false, "Show what would be synced without changing anything")
 
 	updateSecretCmd.Flags().StringP("name", "n", "", "Name of the secret")
 	updateSecretCmd.Flags().StringP("region", "r", "", "AWS region")
@@ -319,10 +670,28 @@ func init() {
 	updateSecretCmd.Flags().StringP("json-file", "j", "", "Path to JSON file containing secret value")
 	updateSecretCmd.Flags().DurationP("timeout", "t", 30*time.Second, "Timeout for the operation")
 	updateSecretCmd.Flags().BoolP("update", "u", false, "Update secret if it already exists")
+	updateSecretCmd.Flags().String("encrypt-with-kms", "", "KMS key ID to client-side encrypt the secret with")
+	updateSecretCmd.Flags().StringArray("encryption-context", nil, "KMS encryption context entry (key=value), repeatable")
 
 	createSecretCmd.Flags().StringP("name", "n", "", "Name of the secret")
 	createSecretCmd.Flags().StringP("region", "r", "", "AWS region")
 	createSecretCmd.Flags().StringP("description", "d", "", "Description of the secret")
 	createSecretCmd.Flags().StringP("json-file", "j", "", "Path to JSON file containing secret value")
 	createSecretCmd.Flags().DurationP("timeout", "t", 30*time.Second, "Timeout for the operation")
+	createSecretCmd.Flags().String("encrypt-with-kms", "", "KMS key ID to client-side encrypt the secret with")
+	createSecretCmd.Flags().StringArray("encryption-context", nil, "KMS encryption context entry (key=value), repeatable")
+
+	getSecretCmd.Flags().StringP("name", "n", "", "Name of the secret")
+	getSecretCmd.Flags().StringP("region", "r", "", "AWS region")
+	getSecretCmd.Flags().DurationP("timeout", "t", 30*time.Second, "Timeout for the operation")
+	getSecretCmd.Flags().StringArray("encryption-context", nil, "KMS encryption context entry (key=value), repeatable")
+
+	rotateSecretCmd.Flags().StringP("name", "n", "", "Name of the secret")
+	rotateSecretCmd.Flags().StringP("region", "r", "", "AWS region")
+	rotateSecretCmd.Flags().DurationP("timeout", "t", 30*time.Second, "Timeout for the operation")
+	rotateSecretCmd.Flags().String("test-cmd", "", "Shell command to verify the pending value before promoting it")
+	rotateSecretCmd.Flags().Bool("generate", false, "Generate the new secret value with Secrets Manager's random password generator")
+	rotateSecretCmd.Flags().Int64("length", 32, "Length of the generated password, used with --generate")
+	rotateSecretCmd.Flags().String("exclude-chars", "", "Characters to exclude from the generated password, used with --generate")
+	rotateSecretCmd.Flags().String("value", "", "New secret value, used instead of --generate")
 }
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/Trev2689/aws-vault/internal/awsauth"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/spf13/cobra"
+)
+
+// newClientRequestToken generates a random idempotency token for
+// PutSecretValue, mirroring what the Secrets Manager console/CLI do when
+// the caller doesn't supply one.
+func newClientRequestToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating client request token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// generateSecretValue asks Secrets Manager for a random password to use as
+// the new pending value, per --generate/--length/--exclude-chars.
+func generateSecretValue(ctx context.Context, client *secretsmanager.Client, length int64, excludeChars string) (string, error) {
+	input := &secretsmanager.GetRandomPasswordInput{
+		PasswordLength: &length,
+	}
+	if excludeChars != "" {
+		input.ExcludeCharacters = &excludeChars
+	}
+
+	out, err := client.GetRandomPassword(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("generating random password: %w", err)
+	}
+	return *out.RandomPassword, nil
+}
+
+// runTestCmd runs the --test-cmd shell hook against the pending secret
+// value, passing it via an environment variable rather than an argument so
+// it doesn't show up in a process listing.
+func runTestCmd(testCmd, pendingValue string) error {
+	cmd := exec.Command("sh", "-c", testCmd)
+	cmd.Env = append(os.Environ(), "AWS_VAULT_PENDING_SECRET="+pendingValue)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// currentVersionID returns the version ID currently holding the AWSCURRENT
+// stage for a secret.
+func currentVersionID(ctx context.Context, client *secretsmanager.Client, secretID string) (string, error) {
+	out, err := client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: &secretID})
+	if err != nil {
+		return "", fmt.Errorf("describing secret: %w", err)
+	}
+	for versionID, stages := range out.VersionIdsToStages {
+		for _, stage := range stages {
+			if stage == "AWSCURRENT" {
+				return versionID, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("secret %s has no AWSCURRENT version", secretID)
+}
+
+// Command to rotate a secret in Secrets Manager without a rotation Lambda,
+// following the createSecret/testSecret/finishSecret stages Secrets Manager
+// expects from a rotation function. There is no setSecret stage: nothing
+// outside Secrets Manager is updated with the new value.
+var rotateSecretCmd = &cobra.Command{
+	Use:   "rotate-secret",
+	Short: "Rotate a secret in Secrets Manager using staged AWSCURRENT/AWSPENDING labels",
+	Run: func(cmd *cobra.Command, args []string) {
+		secretName, _ := cmd.Flags().GetString("name")
+		region, _ := cmd.Flags().GetString("region")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		testCmd, _ := cmd.Flags().GetString("test-cmd")
+		generate, _ := cmd.Flags().GetBool("generate")
+		length, _ := cmd.Flags().GetInt64("length")
+		excludeChars, _ := cmd.Flags().GetString("exclude-chars")
+		value, _ := cmd.Flags().GetString("value")
+
+		if secretName == "" || region == "" {
+			fmt.Println("Please provide all required input parameters: --name and --region")
+			os.Exit(1)
+		}
+		if !generate && value == "" {
+			fmt.Println("Please provide either --generate or --value for the new secret value")
+			os.Exit(1)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		cfg, err := awsauth.LoadConfig(ctx, authOptionsFromFlags(cmd, region))
+		if err != nil {
+			fmt.Println("Error loading AWS SDK config:", err)
+			os.Exit(1)
+		}
+
+		client := secretsmanager.NewFromConfig(cfg)
+
+		oldVersionID, err := currentVersionID(ctx, client, secretName)
+		if err != nil {
+			fmt.Println("Error finding current secret version:", err)
+			os.Exit(1)
+		}
+
+		newValue := value
+		if generate {
+			newValue, err = generateSecretValue(ctx, client, length, excludeChars)
+			if err != nil {
+				fmt.Println("Error generating secret value:", err)
+				os.Exit(1)
+			}
+		}
+
+		token, err := newClientRequestToken()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		// Stage 1: createSecret - stash the new value under AWSPENDING.
+		putOutput, err := client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+			SecretId:           &secretName,
+			ClientRequestToken: &token,
+			SecretString:       &newValue,
+			VersionStages:      []string{"AWSPENDING"},
+		})
+		if err != nil {
+			fmt.Println("Error staging pending secret value:", err)
+			os.Exit(1)
+		}
+		newVersionID := *putOutput.VersionId
+
+		// Stage 2: testSecret - optionally verify the pending value works
+		// before it's promoted.
+		if testCmd != "" {
+			if err := runTestCmd(testCmd, newValue); err != nil {
+				fmt.Println("Test command failed against pending secret value, leaving AWSPENDING in place:", err)
+				os.Exit(1)
+			}
+		}
+
+		// Stage 3: finishSecret - move AWSCURRENT to the new version.
+		if _, err := client.UpdateSecretVersionStage(ctx, &secretsmanager.UpdateSecretVersionStageInput{
+			SecretId:            &secretName,
+			VersionStage:        aws.String("AWSCURRENT"),
+			MoveToVersionId:     &newVersionID,
+			RemoveFromVersionId: &oldVersionID,
+		}); err != nil {
+			fmt.Println("Error promoting new secret version to AWSCURRENT:", err)
+			os.Exit(1)
+		}
+
+		// Stage 4: clean up AWSPENDING on the now-current version.
+		if _, err := client.UpdateSecretVersionStage(ctx, &secretsmanager.UpdateSecretVersionStageInput{
+			SecretId:            &secretName,
+			VersionStage:        aws.String("AWSPENDING"),
+			RemoveFromVersionId: &newVersionID,
+		}); err != nil {
+			fmt.Println("Error removing AWSPENDING stage:", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Successfully rotated secret, new version:", newVersionID)
+	},
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const sseCustomerAlgorithm = "AES256"
+
+// sseOptions carries the server-side and SSE-C encryption settings for a
+// single upload or download. CustomerKey is kept only in memory for the
+// lifetime of the request and must never be logged.
+type sseOptions struct {
+	ServerSideEncryption types.ServerSideEncryption
+	KMSKeyID             string
+	CustomerKey          []byte
+	CustomerKeyMD5       string
+}
+
+// parseSSEFlags builds sseOptions from the --sse, --sse-kms-key-id and
+// --sse-customer-key flag values. customerKeyInput may be a path to a file
+// holding the raw key bytes, or the key itself base64-encoded.
+func parseSSEFlags(sse, kmsKeyID, customerKeyInput string) (*sseOptions, error) {
+	if sse == "" && kmsKeyID == "" && customerKeyInput == "" {
+		return nil, nil
+	}
+
+	opts := &sseOptions{}
+
+	switch strings.ToLower(sse) {
+	case "":
+		// SSE-C can be used without a --sse value.
+	case "aes256":
+		opts.ServerSideEncryption = types.ServerSideEncryptionAes256
+	case "aws:kms":
+		opts.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+	case "aws:kms:dsse":
+		opts.ServerSideEncryption = types.ServerSideEncryptionAwsKmsDsse
+	default:
+		return nil, fmt.Errorf("unsupported --sse value %q (expected AES256, aws:kms or aws:kms:dsse)", sse)
+	}
+	opts.KMSKeyID = kmsKeyID
+
+	if customerKeyInput != "" {
+		key, err := loadCustomerKey(customerKeyInput)
+		if err != nil {
+			// Never include the raw input in the error: it may itself be
+			// the key material if it wasn't a valid file path.
+			return nil, fmt.Errorf("reading SSE customer key: %w", err)
+		}
+		sum := md5.Sum(key)
+		opts.CustomerKey = key
+		opts.CustomerKeyMD5 = base64.StdEncoding.EncodeToString(sum[:])
+	}
+
+	return opts, nil
+}
+
+// loadCustomerKey resolves the --sse-customer-key flag value to raw key
+// bytes, treating it as a file path when the file exists and otherwise as a
+// base64-encoded key.
+func loadCustomerKey(input string) ([]byte, error) {
+	if info, err := os.Stat(input); err == nil && !info.IsDir() {
+		return os.ReadFile(input)
+	}
+	return base64.StdEncoding.DecodeString(input)
+}
+
+// applySSEToPutObjectInput copies SSE/SSE-C settings onto a PutObjectInput.
+func applySSEToPutObjectInput(input *s3.PutObjectInput, sse *sseOptions) {
+	if sse == nil {
+		return
+	}
+	if sse.ServerSideEncryption != "" {
+		input.ServerSideEncryption = sse.ServerSideEncryption
+		if sse.KMSKeyID != "" {
+			input.SSEKMSKeyId = &sse.KMSKeyID
+		}
+	}
+	if sse.CustomerKey != nil {
+		key := base64.StdEncoding.EncodeToString(sse.CustomerKey)
+		alg := sseCustomerAlgorithm
+		md5 := sse.CustomerKeyMD5
+		input.SSECustomerAlgorithm = &alg
+		input.SSECustomerKey = &key
+		input.SSECustomerKeyMD5 = &md5
+	}
+}
+
+// applySSEToUploadPartInput copies the SSE-C settings onto an
+// UploadPartInput; SSE-C requires the customer key on every part.
+func applySSEToUploadPartInput(input *s3.UploadPartInput, sse *sseOptions) {
+	if sse == nil || sse.CustomerKey == nil {
+		return
+	}
+	key := base64.StdEncoding.EncodeToString(sse.CustomerKey)
+	alg := sseCustomerAlgorithm
+	md5 := sse.CustomerKeyMD5
+	input.SSECustomerAlgorithm = &alg
+	input.SSECustomerKey = &key
+	input.SSECustomerKeyMD5 = &md5
+}
+
+// applySSEToHeadObjectInput copies the SSE-C settings onto a
+// HeadObjectInput so metadata for SSE-C objects can be read.
+func applySSEToHeadObjectInput(input *s3.HeadObjectInput, sse *sseOptions) {
+	if sse == nil || sse.CustomerKey == nil {
+		return
+	}
+	key := base64.StdEncoding.EncodeToString(sse.CustomerKey)
+	alg := sseCustomerAlgorithm
+	md5 := sse.CustomerKeyMD5
+	input.SSECustomerAlgorithm = &alg
+	input.SSECustomerKey = &key
+	input.SSECustomerKeyMD5 = &md5
+}
+
+// applySSEToGetObjectInput copies the SSE-C settings onto a GetObjectInput
+// so SSE-C encrypted objects can be retrieved.
+func applySSEToGetObjectInput(input *s3.GetObjectInput, sse *sseOptions) {
+	if sse == nil || sse.CustomerKey == nil {
+		return
+	}
+	key := base64.StdEncoding.EncodeToString(sse.CustomerKey)
+	alg := sseCustomerAlgorithm
+	md5 := sse.CustomerKeyMD5
+	input.SSECustomerAlgorithm = &alg
+	input.SSECustomerKey = &key
+	input.SSECustomerKeyMD5 = &md5
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestApplySSEToPutObjectInputEncodesCustomerKey(t *testing.T) {
+	rawKey := []byte("0123456789abcdef0123456789abcdef")
+	sse := &sseOptions{CustomerKey: rawKey}
+
+	input := &s3.PutObjectInput{}
+	applySSEToPutObjectInput(input, sse)
+
+	if input.SSECustomerKey == nil {
+		t.Fatal("SSECustomerKey not set")
+	}
+	want := base64.StdEncoding.EncodeToString(rawKey)
+	if *input.SSECustomerKey != want {
+		t.Errorf("SSECustomerKey = %q, want base64-encoded %q", *input.SSECustomerKey, want)
+	}
+}
+
+func TestApplySSEToGetObjectInputEncodesCustomerKey(t *testing.T) {
+	rawKey := []byte("0123456789abcdef0123456789abcdef")
+	sse := &sseOptions{CustomerKey: rawKey}
+
+	input := &s3.GetObjectInput{}
+	applySSEToGetObjectInput(input, sse)
+
+	if input.SSECustomerKey == nil {
+		t.Fatal("SSECustomerKey not set")
+	}
+	want := base64.StdEncoding.EncodeToString(rawKey)
+	if *input.SSECustomerKey != want {
+		t.Errorf("SSECustomerKey = %q, want base64-encoded %q", *input.SSECustomerKey, want)
+	}
+}
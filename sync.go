@@ -0,0 +1,466 @@
+package main
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// syncOptions carries the flags shared by sync and sync-down.
+type syncOptions struct {
+	Bucket       string
+	Prefix       string
+	LocalDir     string
+	Workers      int
+	Delete       bool
+	Include      []string
+	Exclude      []string
+	DryRun       bool
+	StorageClass string
+}
+
+// matchesFilters applies --include/--exclude glob filters to a relative
+// (forward-slash) path. A file is synced when it matches at least one
+// --include pattern (or no --include patterns were given) and no --exclude
+// pattern.
+func matchesFilters(relPath string, include, exclude []string) bool {
+	base := path.Base(relPath)
+	for _, pattern := range exclude {
+		if ok, _ := path.Match(pattern, base); ok {
+			return false
+		}
+		if ok, _ := path.Match(pattern, relPath); ok {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, relPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// localFileSHA256 hashes a local file's contents.
+func localFileSHA256(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// localFileMD5 hashes a local file's contents, matching the ETag format S3
+// uses for objects that weren't uploaded as multipart.
+func localFileMD5(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// objectUnchanged compares a local file against the HeadObject result for
+// its S3 key, preferring the SHA-256 checksum when the object has one and
+// otherwise falling back to comparing MD5 against the ETag.
+func objectUnchanged(filePath string, head *s3.HeadObjectOutput) (bool, error) {
+	if head.ChecksumSHA256 != nil {
+		localDigest, err := localFileSHA256(filePath)
+		if err != nil {
+			return false, err
+		}
+		sum, err := base64DecodeToHex(*head.ChecksumSHA256)
+		if err != nil {
+			return false, err
+		}
+		return localDigest == sum, nil
+	}
+
+	if head.ETag == nil {
+		return false, nil
+	}
+	etag := strings.Trim(*head.ETag, `"`)
+	if strings.Contains(etag, "-") {
+		// Multipart ETags aren't a plain MD5 of the object; without the
+		// part layout we can't cheaply verify content equality, so treat
+		// it as changed and let the upload overwrite it.
+		return false, nil
+	}
+	localDigest, err := localFileMD5(filePath)
+	if err != nil {
+		return false, err
+	}
+	return localDigest == etag, nil
+}
+
+// base64DecodeToHex converts a base64-encoded checksum (as returned by S3)
+// into the lowercase hex form produced by crypto/sha256.
+func base64DecodeToHex(b64 string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// syncResult tallies what a sync run did, so --dry-run and real runs can
+// share the same summary line.
+type syncResult struct {
+	mu          sync.Mutex
+	Transferred int
+	Skipped     int
+	Deleted     int
+	Errors      []error
+}
+
+func (r *syncResult) recordError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Errors = append(r.Errors, err)
+}
+
+func (r *syncResult) increment(field *int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*field++
+}
+
+// runSyncUp walks opts.LocalDir and uploads changed files to
+// opts.Bucket/opts.Prefix using a bounded pool of worker goroutines.
+func runSyncUp(ctx context.Context, client *s3.Client, opts syncOptions) (*syncResult, error) {
+	if opts.Workers < 1 {
+		return nil, fmt.Errorf("--workers must be at least 1, got %d", opts.Workers)
+	}
+
+	result := &syncResult{}
+
+	type job struct {
+		localPath string
+		key       string
+	}
+	jobs := make(chan job)
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				syncUpOne(ctx, client, opts, j.localPath, j.key, result)
+			}
+		}()
+	}
+
+	localKeys := make(map[string]bool)
+	walkErr := filepath.WalkDir(opts.LocalDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(opts.LocalDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		key := rel
+		if opts.Prefix != "" {
+			key = strings.TrimSuffix(opts.Prefix, "/") + "/" + rel
+		}
+		localKeys[key] = true
+
+		if !matchesFilters(rel, opts.Include, opts.Exclude) {
+			return nil
+		}
+
+		jobs <- job{localPath: p, key: key}
+		return nil
+	})
+	close(jobs)
+	wg.Wait()
+
+	if walkErr != nil {
+		return result, fmt.Errorf("walking %s: %w", opts.LocalDir, walkErr)
+	}
+
+	if opts.Delete {
+		if err := deleteMissingKeys(ctx, client, opts, localKeys, result); err != nil {
+			return result, err
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		return result, fmt.Errorf("%d file(s) failed to sync: %w", len(result.Errors), errors.Join(result.Errors...))
+	}
+	return result, nil
+}
+
+func syncUpOne(ctx context.Context, client *s3.Client, opts syncOptions, localPath, key string, result *syncResult) {
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &opts.Bucket, Key: &key, ChecksumMode: types.ChecksumModeEnabled})
+	if err == nil {
+		unchanged, hashErr := objectUnchanged(localPath, head)
+		if hashErr != nil {
+			result.recordError(fmt.Errorf("%s: %w", localPath, hashErr))
+			return
+		}
+		if unchanged {
+			result.increment(&result.Skipped)
+			return
+		}
+	}
+
+	if opts.DryRun {
+		fmt.Println("Would upload:", key)
+		result.increment(&result.Transferred)
+		return
+	}
+
+	f, err := os.Open(localPath)
+	if err != nil {
+		result.recordError(fmt.Errorf("%s: %w", localPath, err))
+		return
+	}
+	defer f.Close()
+
+	input := &s3.PutObjectInput{
+		Bucket: &opts.Bucket,
+		Key:    &key,
+		Body:   f,
+	}
+	if opts.StorageClass != "" {
+		input.StorageClass = types.StorageClass(opts.StorageClass)
+	}
+
+	if _, err := client.PutObject(ctx, input); err != nil {
+		result.recordError(fmt.Errorf("uploading %s: %w", key, err))
+		return
+	}
+
+	fmt.Println("Uploaded:", key)
+	result.increment(&result.Transferred)
+}
+
+// listPrefix normalizes opts.Prefix to a trailing-slash directory prefix so
+// ListObjectsV2 only matches keys under that tree, not unrelated sibling
+// keys that merely share the same string prefix (e.g. "myapp" also matching
+// "myappbackup/...").
+func listPrefix(prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+	return strings.TrimSuffix(prefix, "/") + "/"
+}
+
+// deleteMissingKeys removes S3 objects under opts.Prefix that have no
+// corresponding local file, when --delete was requested.
+func deleteMissingKeys(ctx context.Context, client *s3.Client, opts syncOptions, localKeys map[string]bool, result *syncResult) error {
+	prefix := listPrefix(opts.Prefix)
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: &opts.Bucket,
+		Prefix: &prefix,
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("listing objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			key := *obj.Key
+			if localKeys[key] {
+				continue
+			}
+			if opts.DryRun {
+				fmt.Println("Would delete:", key)
+				result.increment(&result.Deleted)
+				continue
+			}
+			if _, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &opts.Bucket, Key: &key}); err != nil {
+				result.recordError(fmt.Errorf("deleting %s: %w", key, err))
+				continue
+			}
+			fmt.Println("Deleted:", key)
+			result.increment(&result.Deleted)
+		}
+	}
+	return nil
+}
+
+// runSyncDown lists opts.Bucket/opts.Prefix and downloads changed objects
+// into opts.LocalDir using a bounded pool of worker goroutines.
+func runSyncDown(ctx context.Context, client *s3.Client, opts syncOptions) (*syncResult, error) {
+	if opts.Workers < 1 {
+		return nil, fmt.Errorf("--workers must be at least 1, got %d", opts.Workers)
+	}
+
+	result := &syncResult{}
+
+	type job struct {
+		key       string
+		localPath string
+	}
+	jobs := make(chan job)
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				syncDownOne(ctx, client, opts, j.key, j.localPath, result)
+			}
+		}()
+	}
+
+	remoteFiles := make(map[string]bool)
+	var listErr error
+	go func() {
+		defer close(jobs)
+		prefix := listPrefix(opts.Prefix)
+		paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+			Bucket: &opts.Bucket,
+			Prefix: &prefix,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				listErr = fmt.Errorf("listing objects: %w", err)
+				return
+			}
+			for _, obj := range page.Contents {
+				key := *obj.Key
+				rel := strings.TrimPrefix(key, prefix)
+				localPath := filepath.Join(opts.LocalDir, filepath.FromSlash(rel))
+				remoteFiles[localPath] = true
+				if !matchesFilters(rel, opts.Include, opts.Exclude) {
+					continue
+				}
+				jobs <- job{key: key, localPath: localPath}
+			}
+		}
+	}()
+	wg.Wait()
+
+	if listErr != nil {
+		return result, listErr
+	}
+
+	if opts.Delete {
+		if err := deleteMissingLocalFiles(opts, remoteFiles, result); err != nil {
+			return result, err
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		return result, fmt.Errorf("%d object(s) failed to sync: %w", len(result.Errors), errors.Join(result.Errors...))
+	}
+	return result, nil
+}
+
+func syncDownOne(ctx context.Context, client *s3.Client, opts syncOptions, key, localPath string, result *syncResult) {
+	if info, err := os.Stat(localPath); err == nil && !info.IsDir() {
+		head, err := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &opts.Bucket, Key: &key, ChecksumMode: types.ChecksumModeEnabled})
+		if err == nil {
+			unchanged, hashErr := objectUnchanged(localPath, head)
+			if hashErr == nil && unchanged {
+				result.increment(&result.Skipped)
+				return
+			}
+		}
+	}
+
+	if opts.DryRun {
+		fmt.Println("Would download:", key)
+		result.increment(&result.Transferred)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		result.recordError(fmt.Errorf("creating directory for %s: %w", localPath, err))
+		return
+	}
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: &opts.Bucket, Key: &key})
+	if err != nil {
+		result.recordError(fmt.Errorf("downloading %s: %w", key, err))
+		return
+	}
+	defer out.Body.Close()
+
+	f, err := os.Create(localPath)
+	if err != nil {
+		result.recordError(fmt.Errorf("creating %s: %w", localPath, err))
+		return
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, out.Body); err != nil {
+		result.recordError(fmt.Errorf("writing %s: %w", localPath, err))
+		return
+	}
+
+	fmt.Println("Downloaded:", key)
+	result.increment(&result.Transferred)
+}
+
+// deleteMissingLocalFiles removes local files that have no corresponding
+// S3 object, when --delete was requested with sync-down.
+func deleteMissingLocalFiles(opts syncOptions, remoteFiles map[string]bool, result *syncResult) error {
+	return filepath.WalkDir(opts.LocalDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if remoteFiles[p] {
+			return nil
+		}
+		if opts.DryRun {
+			fmt.Println("Would delete:", p)
+			result.increment(&result.Deleted)
+			return nil
+		}
+		if err := os.Remove(p); err != nil {
+			result.recordError(fmt.Errorf("deleting %s: %w", p, err))
+			return nil
+		}
+		fmt.Println("Deleted:", p)
+		result.increment(&result.Deleted)
+		return nil
+	})
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestListPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		want   string
+	}{
+		{"empty", "", ""},
+		{"no trailing slash", "myapp", "myapp/"},
+		{"trailing slash", "myapp/", "myapp/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listPrefix(tt.prefix); got != tt.want {
+				t.Errorf("listPrefix(%q) = %q, want %q", tt.prefix, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		relPath string
+		include []string
+		exclude []string
+		want    bool
+	}{
+		{"no filters", "data/file.txt", nil, nil, true},
+		{"excluded by base name", "data/file.log", nil, []string{"*.log"}, false},
+		{"excluded by full path", "data/file.txt", nil, []string{"data/*"}, false},
+		{"included by base name", "data/file.txt", []string{"*.txt"}, nil, true},
+		{"not in include list", "data/file.txt", []string{"*.csv"}, nil, false},
+		{"exclude wins over include", "data/file.txt", []string{"*.txt"}, []string{"data/*"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesFilters(tt.relPath, tt.include, tt.exclude); got != tt.want {
+				t.Errorf("matchesFilters(%q, %v, %v) = %v, want %v", tt.relPath, tt.include, tt.exclude, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBase64DecodeToHex(t *testing.T) {
+	// "\x01\x02\x03" base64-encoded.
+	got, err := base64DecodeToHex("AQID")
+	if err != nil {
+		t.Fatalf("base64DecodeToHex returned error: %v", err)
+	}
+	if want := "010203"; got != want {
+		t.Errorf("base64DecodeToHex(%q) = %q, want %q", "AQID", got, want)
+	}
+
+	if _, err := base64DecodeToHex("not-base64!!"); err == nil {
+		t.Error("base64DecodeToHex(invalid) expected error, got nil")
+	}
+}
+
+func TestObjectUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/file.txt"
+	if err := os.WriteFile(path, []byte("hello world"), 0600); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+
+	t.Run("matching plain ETag", func(t *testing.T) {
+		etag := `"5eb63bbbe01eeed093cb22bb8f5acdc3"`
+		unchanged, err := objectUnchanged(path, &s3.HeadObjectOutput{ETag: &etag})
+		if err != nil {
+			t.Fatalf("objectUnchanged returned error: %v", err)
+		}
+		if !unchanged {
+			t.Error("expected file to be reported unchanged")
+		}
+	})
+
+	t.Run("mismatched plain ETag", func(t *testing.T) {
+		etag := `"deadbeefdeadbeefdeadbeefdeadbeef"`
+		unchanged, err := objectUnchanged(path, &s3.HeadObjectOutput{ETag: &etag})
+		if err != nil {
+			t.Fatalf("objectUnchanged returned error: %v", err)
+		}
+		if unchanged {
+			t.Error("expected file to be reported changed")
+		}
+	})
+
+	t.Run("multipart ETag always changed", func(t *testing.T) {
+		etag := `"abcdef0123456789abcdef0123456789-2"`
+		unchanged, err := objectUnchanged(path, &s3.HeadObjectOutput{ETag: &etag})
+		if err != nil {
+			t.Fatalf("objectUnchanged returned error: %v", err)
+		}
+		if unchanged {
+			t.Error("expected multipart ETag to be treated as changed")
+		}
+	})
+
+	t.Run("no ETag", func(t *testing.T) {
+		unchanged, err := objectUnchanged(path, &s3.HeadObjectOutput{})
+		if err != nil {
+			t.Fatalf("objectUnchanged returned error: %v", err)
+		}
+		if unchanged {
+			t.Error("expected missing ETag to be treated as changed")
+		}
+	})
+}
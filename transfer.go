@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/schollz/progressbar/v3"
+)
+
+const (
+	defaultPartSizeMB  = 5
+	defaultConcurrency = 5
+)
+
+// resumeState tracks the in-progress multipart upload for a given
+// bucket/key/file so an interrupted transfer can pick up where it left off.
+type resumeState struct {
+	UploadID string                `json:"upload_id"`
+	Parts    []types.CompletedPart `json:"parts"`
+}
+
+// resumeStateDir returns the directory used to persist resumeState files,
+// creating it if necessary.
+func resumeStateDir() (string, error) {
+	dir := filepath.Join(os.TempDir(), "aws-vault-resume")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// resumeStateKey derives a stable file name for a bucket+key+mtime tuple so
+// re-running the same upload finds its previous progress.
+func resumeStateKey(bucket, key string, mtime int64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d", bucket, key, mtime)))
+	return fmt.Sprintf("%x.json", sum)
+}
+
+func loadResumeState(path string) (*resumeState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state resumeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func saveResumeState(path string, state *resumeState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func clearResumeState(path string) {
+	os.Remove(path)
+}
+
+// parseChecksumAlgorithm maps the --checksum-algorithm flag value onto the
+// SDK's ChecksumAlgorithm type. An empty string disables checksums.
+func parseChecksumAlgorithm(value string) (types.ChecksumAlgorithm, error) {
+	switch strings.ToUpper(value) {
+	case "":
+		return "", nil
+	case "CRC32":
+		return types.ChecksumAlgorithmCrc32, nil
+	case "CRC32C":
+		return types.ChecksumAlgorithmCrc32c, nil
+	case "SHA256":
+		return types.ChecksumAlgorithmSha256, nil
+	default:
+		return "", fmt.Errorf("unsupported checksum algorithm %q (expected CRC32, CRC32C or SHA256)", value)
+	}
+}
+
+// newProgressBar builds a byte-denominated progress bar for the given
+// transfer size, or nil when progress reporting is disabled.
+func newProgressBar(show bool, size int64, description string) *progressbar.ProgressBar {
+	if !show {
+		return nil
+	}
+	return progressbar.DefaultBytes(size, description)
+}
+
+// uploadFileStreaming uploads filePath to bucket/key using the S3 transfer
+// manager so large files are streamed in parts rather than read fully into
+// memory. When a previous invocation left a resume state file behind (e.g.
+// the process was interrupted partway through), it falls back to the raw
+// multipart APIs so only the missing parts are re-uploaded.
+func uploadFileStreaming(client *s3.Client, bucket, key, filePath string, partSizeMB int64, concurrency int, checksumAlgo types.ChecksumAlgorithm, showProgress bool, sse *sseOptions) error {
+	ctx := context.Background()
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stating file: %w", err)
+	}
+
+	stateDir, err := resumeStateDir()
+	if err != nil {
+		return fmt.Errorf("preparing resume state directory: %w", err)
+	}
+	statePath := filepath.Join(stateDir, resumeStateKey(bucket, key, info.ModTime().Unix()))
+
+	existing, err := loadResumeState(statePath)
+	if err != nil {
+		return fmt.Errorf("reading resume state: %w", err)
+	}
+	if existing != nil {
+		return resumeMultipartUpload(ctx, client, bucket, key, f, info.Size(), partSizeMB, checksumAlgo, showProgress, statePath, existing, sse)
+	}
+
+	partSize := partSizeMB * 1024 * 1024
+	uploader := manager.NewUploader(client, func(u *manager.Uploader) {
+		u.PartSize = partSize
+		u.Concurrency = concurrency
+		u.LeavePartsOnError = true
+	})
+
+	var body io.Reader = f
+	bar := newProgressBar(showProgress, info.Size(), "uploading")
+	if bar != nil {
+		body = io.TeeReader(f, bar)
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Body:   body,
+	}
+	if checksumAlgo != "" {
+		input.ChecksumAlgorithm = checksumAlgo
+	}
+	applySSEToPutObjectInput(input, sse)
+
+	_, err = uploader.Upload(ctx, input)
+	if err != nil {
+		// On a genuine multipart failure the SDK returns no output and
+		// instead stuffs the upload ID into the error; record it so a
+		// retry can resume instead of starting over.
+		var mu manager.MultiUploadFailure
+		if errors.As(err, &mu) {
+			_ = saveResumeState(statePath, &resumeState{UploadID: mu.UploadID()})
+		}
+		return fmt.Errorf("uploading: %w", err)
+	}
+
+	clearResumeState(statePath)
+	return nil
+}
+
+// resumeMultipartUpload continues a multipart upload that a previous run
+// started, listing the parts S3 already has and only sending the rest.
+func resumeMultipartUpload(ctx context.Context, client *s3.Client, bucket, key string, f *os.File, size int64, partSizeMB int64, checksumAlgo types.ChecksumAlgorithm, showProgress bool, statePath string, state *resumeState, sse *sseOptions) error {
+	partSize := partSizeMB * 1024 * 1024
+
+	listed, err := client.ListParts(ctx, &s3.ListPartsInput{
+		Bucket:   &bucket,
+		Key:      &key,
+		UploadId: &state.UploadID,
+	})
+	if err != nil {
+		return fmt.Errorf("listing existing parts: %w", err)
+	}
+
+	done := make(map[int32]types.CompletedPart)
+	for _, p := range listed.Parts {
+		done[*p.PartNumber] = types.CompletedPart{
+			ETag:       p.ETag,
+			PartNumber: p.PartNumber,
+		}
+	}
+
+	var bar *progressbar.ProgressBar
+	if showProgress {
+		bar = progressbar.DefaultBytes(size, "uploading (resumed)")
+		for _, p := range listed.Parts {
+			bar.Add64(*p.Size)
+		}
+	}
+
+	totalParts := int32((size + partSize - 1) / partSize)
+	var completed []types.CompletedPart
+	for partNumber := int32(1); partNumber <= totalParts; partNumber++ {
+		if existing, ok := done[partNumber]; ok {
+			completed = append(completed, existing)
+			continue
+		}
+
+		offset := int64(partNumber-1) * partSize
+		length := partSize
+		if offset+length > size {
+			length = size - offset
+		}
+
+		section := io.NewSectionReader(f, offset, length)
+		var body io.Reader = section
+		if bar != nil {
+			body = io.TeeReader(section, bar)
+		}
+
+		partInput := &s3.UploadPartInput{
+			Bucket:     &bucket,
+			Key:        &key,
+			UploadId:   &state.UploadID,
+			PartNumber: &partNumber,
+			Body:       body,
+		}
+		if checksumAlgo != "" {
+			partInput.ChecksumAlgorithm = checksumAlgo
+		}
+		applySSEToUploadPartInput(partInput, sse)
+
+		uploadOut, err := client.UploadPart(ctx, partInput)
+		if err != nil {
+			_ = saveResumeState(statePath, &resumeState{UploadID: state.UploadID, Parts: completed})
+			return fmt.Errorf("uploading part %d: %w", partNumber, err)
+		}
+
+		completed = append(completed, types.CompletedPart{ETag: uploadOut.ETag, PartNumber: &partNumber})
+		_ = saveResumeState(statePath, &resumeState{UploadID: state.UploadID, Parts: completed})
+	}
+
+	_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   &bucket,
+		Key:      &key,
+		UploadId: &state.UploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: completed,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("completing multipart upload: %w", err)
+	}
+
+	clearResumeState(statePath)
+	return nil
+}
+
+// downloadFileStreaming downloads bucket/key to filePath using the S3
+// transfer manager so large objects are fetched as concurrent ranged parts
+// instead of being buffered fully in memory.
+func downloadFileStreaming(client *s3.Client, bucket, key, filePath string, partSizeMB int64, concurrency int, showProgress bool, sse *sseOptions) error {
+	ctx := context.Background()
+
+	headInput := &s3.HeadObjectInput{Bucket: &bucket, Key: &key}
+	applySSEToHeadObjectInput(headInput, sse)
+	out, err := client.HeadObject(ctx, headInput)
+	if err != nil {
+		return fmt.Errorf("checking object: %w", err)
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	defer f.Close()
+
+	downloader := manager.NewDownloader(client, func(d *manager.Downloader) {
+		d.PartSize = partSizeMB * 1024 * 1024
+		d.Concurrency = concurrency
+	})
+
+	var writer io.WriterAt = f
+	if showProgress && out.ContentLength != nil {
+		bar := progressbar.DefaultBytes(*out.ContentLength, "downloading")
+		writer = &progressWriterAt{writerAt: f, bar: bar}
+	}
+
+	getInput := &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	}
+	applySSEToGetObjectInput(getInput, sse)
+
+	_, err = downloader.Download(ctx, writer, getInput)
+	if err != nil {
+		return fmt.Errorf("downloading: %w", err)
+	}
+	return nil
+}
+
+// progressWriterAt reports bytes written to a progress bar, which the
+// manager drives through io.WriterAt to support concurrent ranged writes.
+type progressWriterAt struct {
+	writerAt io.WriterAt
+	bar      *progressbar.ProgressBar
+}
+
+func (p *progressWriterAt) WriteAt(b []byte, off int64) (int, error) {
+	n, err := p.writerAt.WriteAt(b, off)
+	if n > 0 {
+		p.bar.Add(n)
+	}
+	return n, err
+}